@@ -0,0 +1,74 @@
+// +build go1.16
+
+package iofs
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+func TestDriverChecksum(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+
+	d, err := NewDriverWithOptions(fsys, "migrations", Options{Hash: crypto.SHA256})
+	if err != nil {
+		t.Fatalf("NewDriverWithOptions: %v", err)
+	}
+	drv := d.(*Driver)
+
+	want := sha256.Sum256([]byte("SELECT 1"))
+	got, err := drv.Checksum(1, source.Up)
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	if string(got) != string(want[:]) {
+		t.Fatalf("checksum mismatch: got %x, want %x", got, want)
+	}
+
+	r, _, err := drv.ReadUp(1)
+	if err != nil {
+		t.Fatalf("ReadUp: %v", err)
+	}
+	defer r.Close()
+}
+
+func TestDriverChecksumNotEnabled(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+
+	d, err := NewDriver(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("NewDriver: %v", err)
+	}
+	drv := d.(*Driver)
+
+	if _, err := drv.Checksum(1, source.Up); err == nil {
+		t.Fatal("expected error when hashing is not enabled")
+	}
+}
+
+func TestDriverChecksumDetectsDrift(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+
+	d, err := NewDriverWithOptions(fsys, "migrations", Options{Hash: crypto.SHA256})
+	if err != nil {
+		t.Fatalf("NewDriverWithOptions: %v", err)
+	}
+	drv := d.(*Driver)
+
+	// Simulate the underlying file changing after Init without re-running it.
+	fsys["migrations/1_init.up.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE users")}
+
+	if _, _, err := drv.ReadUp(1); err == nil {
+		t.Fatal("expected checksum mismatch error after file contents changed")
+	}
+}
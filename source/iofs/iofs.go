@@ -3,6 +3,8 @@
 package iofs
 
 import (
+	"bytes"
+	"crypto"
 	"errors"
 	"fmt"
 	"io"
@@ -13,11 +15,29 @@ import (
 	"github.com/golang-migrate/migrate/v4/source"
 )
 
+// Options configures optional behavior of a Driver, such as integrity
+// verification of migration files.
+type Options struct {
+	// Hash, when non-zero, enables checksumming of migration files during
+	// Init and verification of their contents on every subsequent read.
+	// The chosen hash's package must be imported by the caller so it is
+	// registered with the crypto package (e.g. _ "crypto/sha256").
+	Hash crypto.Hash
+}
+
+// digestKey identifies the cached digest of a single migration file.
+type digestKey struct {
+	version   uint
+	direction source.Direction
+}
+
 // Driver is a source driver that wraps io/fs#FS.
 type Driver struct {
 	migrations *source.Migrations
 	fsys       fs.FS
 	path       string
+	options    Options
+	digests    map[digestKey][]byte
 }
 
 // NewDriver returns a new Driver from io/fs#FS and a relative path.
@@ -29,6 +49,17 @@ func NewDriver(fsys fs.FS, path string) (source.Driver, error) {
 	return &i, nil
 }
 
+// NewDriverWithOptions returns a new Driver from io/fs#FS and a relative
+// path, configured with opts. Use this instead of NewDriver to enable
+// checksumming via Options.Hash.
+func NewDriverWithOptions(fsys fs.FS, path string, opts Options) (source.Driver, error) {
+	var i Driver
+	if err := i.initWithOptions(fsys, path, opts); err != nil {
+		return nil, fmt.Errorf("failed to init driver with path %s: %w", path, err)
+	}
+	return &i, nil
+}
+
 // Open is part of source.Driver interface implementation.
 // Open panics when called directly.
 func (i *Driver) Open(url string) (source.Driver, error) {
@@ -38,12 +69,20 @@ func (i *Driver) Open(url string) (source.Driver, error) {
 // Init prepares not initialized IoFS instance to read migrations from a
 // io/fs#FS instance and a relative path.
 func (i *Driver) Init(fsys fs.FS, path string) error {
-	entries, err := fs.ReadDir(fsys, path)
+	return i.initWithOptions(fsys, path, Options{})
+}
+
+// initWithOptions is the shared implementation behind Init and
+// NewDriverWithOptions. When opts.Hash is set, it additionally computes
+// and caches a digest of every migration file's contents.
+func (i *Driver) initWithOptions(fsys fs.FS, p string, opts Options) error {
+	entries, err := fs.ReadDir(fsys, p)
 	if err != nil {
 		return err
 	}
 
 	ms := source.NewMigrations()
+	digests := make(map[digestKey][]byte)
 	for _, e := range entries {
 		if e.IsDir() {
 			continue
@@ -62,14 +101,36 @@ func (i *Driver) Init(fsys fs.FS, path string) error {
 				FileInfo:  file,
 			}
 		}
+		if opts.Hash != 0 {
+			sum, err := hashFile(fsys, path.Join(p, m.Raw), opts.Hash)
+			if err != nil {
+				return err
+			}
+			digests[digestKey{version: m.Version, direction: m.Direction}] = sum
+		}
 	}
 
 	i.fsys = fsys
-	i.path = path
+	i.path = p
 	i.migrations = ms
+	i.options = opts
+	i.digests = digests
 	return nil
 }
 
+// hashFile reads name from fsys in full and returns its digest under h.
+func hashFile(fsys fs.FS, name string, h crypto.Hash) ([]byte, error) {
+	contents, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	hasher := h.New()
+	if _, err := hasher.Write(contents); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
 // Close is part of source.Driver interface implementation.
 // Closes the file system if possible.
 func (d *Driver) Close() error {
@@ -116,10 +177,54 @@ func (i *Driver) Next(version uint) (nextVersion uint, err error) {
 	}
 }
 
+// GetUnappliedMigrations returns every known up-migration version strictly
+// greater than current, in ascending order. It saves callers from having
+// to repeatedly call Next and handle fs.ErrNotExist just to build a
+// "pending" list for status reporting. Versions that only have a down
+// migration are not up-migrations and are excluded.
+func (i *Driver) GetUnappliedMigrations(current uint) []uint {
+	var pending []uint
+	version, err := i.First()
+	for err == nil {
+		if version > current {
+			if _, ok := i.migrations.Up(version); ok {
+				pending = append(pending, version)
+			}
+		}
+		version, err = i.Next(version)
+	}
+	return pending
+}
+
+// PendingCount returns the number of up-migrations strictly greater than
+// current. It is shorthand for len(GetUnappliedMigrations(current)) for
+// callers that only need a count, and does the same work.
+func (i *Driver) PendingCount(current uint) int {
+	return len(i.GetUnappliedMigrations(current))
+}
+
+// Checksum returns the cached digest of the migration file for version and
+// direction, computed during Init when Options.Hash was set. It returns an
+// error if hashing was not enabled or the version/direction is unknown.
+func (i *Driver) Checksum(version uint, direction source.Direction) ([]byte, error) {
+	if i.options.Hash == 0 {
+		return nil, errors.New("iofs: checksumming not enabled, pass Options.Hash to NewDriverWithOptions")
+	}
+	sum, ok := i.digests[digestKey{version: version, direction: direction}]
+	if !ok {
+		return nil, &fs.PathError{
+			Op:   "checksum for version " + strconv.FormatUint(uint64(version), 10),
+			Path: i.path,
+			Err:  fs.ErrNotExist,
+		}
+	}
+	return sum, nil
+}
+
 // ReadUp is part of source.Driver interface implementation.
 func (i *Driver) ReadUp(version uint) (r io.ReadCloser, identifier string, err error) {
 	if m, ok := i.migrations.Up(version); ok {
-		body, err := i.open(path.Join(i.path, m.Raw))
+		body, err := i.openVerified(path.Join(i.path, m.Raw), version, m.Direction)
 		if err != nil {
 			return nil, "", err
 		}
@@ -135,7 +240,7 @@ func (i *Driver) ReadUp(version uint) (r io.ReadCloser, identifier string, err e
 // ReadDown is part of source.Driver interface implementation.
 func (i *Driver) ReadDown(version uint) (r io.ReadCloser, identifier string, err error) {
 	if m, ok := i.migrations.Down(version); ok {
-		body, err := i.open(path.Join(i.path, m.Raw))
+		body, err := i.openVerified(path.Join(i.path, m.Raw), version, m.Direction)
 		if err != nil {
 			return nil, "", err
 		}
@@ -148,6 +253,53 @@ func (i *Driver) ReadDown(version uint) (r io.ReadCloser, identifier string, err
 	}
 }
 
+// openVerified behaves like open, but when checksumming is enabled it reads
+// the file in full and compares its digest against the one cached during
+// Init, returning an error if they differ. This catches the source FS
+// having been swapped out from under the driver mid-run.
+func (i *Driver) openVerified(name string, version uint, direction source.Direction) (fs.File, error) {
+	f, err := i.open(name)
+	if err != nil {
+		return nil, err
+	}
+	if i.options.Hash == 0 {
+		return f, nil
+	}
+	defer f.Close()
+
+	contents, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	hasher := i.options.Hash.New()
+	if _, err := hasher.Write(contents); err != nil {
+		return nil, err
+	}
+	sum := hasher.Sum(nil)
+
+	want, ok := i.digests[digestKey{version: version, direction: direction}]
+	if ok && !bytes.Equal(sum, want) {
+		return nil, &fs.PathError{
+			Op:   "checksum mismatch for version " + strconv.FormatUint(uint64(version), 10),
+			Path: name,
+			Err:  errors.New("migration file contents changed since Init"),
+		}
+	}
+	return nopSeekCloser{Reader: bytes.NewReader(contents)}, nil
+}
+
+// nopSeekCloser adapts a bytes.Reader to fs.File so openVerified can hand
+// back the already-read contents without keeping the underlying file open.
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func (nopSeekCloser) Stat() (fs.FileInfo, error) {
+	return nil, errors.New("iofs: Stat not supported on verified migration reader")
+}
+
 func (i *Driver) open(path string) (fs.File, error) {
 	f, err := i.fsys.Open(path)
 	if err == nil {
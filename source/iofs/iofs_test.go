@@ -0,0 +1,69 @@
+// +build go1.16
+
+package iofs
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestDriverGetUnappliedMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.sql":         {Data: []byte("SELECT 1")},
+		"migrations/5_add_column.up.sql":   {Data: []byte("SELECT 1")},
+		"migrations/20_add_index.up.sql":   {Data: []byte("SELECT 1")},
+		"migrations/20_add_index.down.sql": {Data: []byte("SELECT 1")},
+	}
+
+	d, err := NewDriver(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("NewDriver: %v", err)
+	}
+	drv := d.(*Driver)
+
+	pending := drv.GetUnappliedMigrations(0)
+	want := []uint{1, 5, 20}
+	if len(pending) != len(want) {
+		t.Fatalf("expected %v, got %v", want, pending)
+	}
+	for i := range want {
+		if pending[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, pending)
+		}
+	}
+
+	pending = drv.GetUnappliedMigrations(5)
+	want = []uint{20}
+	if len(pending) != len(want) || pending[0] != want[0] {
+		t.Fatalf("expected %v, got %v", want, pending)
+	}
+
+	if got := drv.PendingCount(0); got != 3 {
+		t.Fatalf("expected PendingCount(0) == 3, got %d", got)
+	}
+	if got := drv.PendingCount(20); got != 0 {
+		t.Fatalf("expected PendingCount(20) == 0, got %d", got)
+	}
+}
+
+func TestDriverGetUnappliedMigrationsExcludesDownOnlyVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_init.up.sql":     {Data: []byte("SELECT 1")},
+		"migrations/2_revert.down.sql": {Data: []byte("SELECT 1")},
+	}
+
+	d, err := NewDriver(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("NewDriver: %v", err)
+	}
+	drv := d.(*Driver)
+
+	pending := drv.GetUnappliedMigrations(0)
+	want := []uint{1}
+	if len(pending) != len(want) || pending[0] != want[0] {
+		t.Fatalf("expected %v (version 2 has no up migration), got %v", want, pending)
+	}
+	if got := drv.PendingCount(0); got != 1 {
+		t.Fatalf("expected PendingCount(0) == 1, got %d", got)
+	}
+}
@@ -0,0 +1,243 @@
+// +build go1.16
+
+package iofs
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// mount describes a single io/fs#FS bound into a NameSpace at a given
+// logical prefix and subpath.
+type mount struct {
+	prefix string
+	fsys   fs.FS
+	path   string
+}
+
+// mountKey identifies which mount owns a given migration version and
+// direction. Keying by version alone would be wrong: a version can be
+// legitimately split across mounts (e.g. one mount provides the up
+// migration, another the down migration for the same version), and
+// source.Migrations dedups by (version, direction), not version alone.
+type mountKey struct {
+	version   uint
+	direction source.Direction
+}
+
+// NameSpace is a source driver that composes migrations from multiple
+// io/fs#FS instances, each bound at its own prefix and subpath, into a
+// single ordered set of migrations. This lets an application ship core
+// migrations from one embed.FS and plugin- or tenant-specific migrations
+// from others, layering them at runtime the way a VFS namespace layers
+// mount points.
+type NameSpace struct {
+	migrations *source.Migrations
+	mounts     []mount
+	owner      map[mountKey]int
+}
+
+// NewNameSpace returns an empty NameSpace ready to have filesystems bound
+// into it via Bind.
+func NewNameSpace() *NameSpace {
+	return &NameSpace{
+		migrations: source.NewMigrations(),
+		owner:      make(map[mountKey]int),
+	}
+}
+
+// Open is part of source.Driver interface implementation.
+// Open panics when called directly.
+func (n *NameSpace) Open(url string) (source.Driver, error) {
+	panic("iofs: NameSpace does not support open with url")
+}
+
+// Bind merges the migrations found under subpath in fsys into the
+// NameSpace, recording prefix as the mount's logical name; the prefix is
+// used to label that mount's errors in Close. Duplicate migrations (same
+// version and direction), whether within this mount or across previously
+// bound mounts, are rejected via source.ErrDuplicateMigration.
+//
+// Bind is transactional: if any entry in this mount turns out to be a
+// duplicate, the NameSpace is left exactly as it was before the call, with
+// nothing from this mount registered.
+func (n *NameSpace) Bind(prefix string, fsys fs.FS, subpath string) error {
+	entries, err := fs.ReadDir(fsys, subpath)
+	if err != nil {
+		return err
+	}
+
+	// Stage into a scratch set first so a duplicate found partway through
+	// this mount's entries can't leave n.migrations/n.owner pointing at a
+	// mount that never makes it into n.mounts. FileInfo travels alongside
+	// each migration so a duplicate found later, against already-bound
+	// mounts, can still populate source.ErrDuplicateMigration fully.
+	staged := source.NewMigrations()
+	var parsed []struct {
+		m    *source.Migration
+		file os.FileInfo
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m, err := source.DefaultParse(e.Name())
+		if err != nil {
+			continue
+		}
+		file, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if !staged.Append(m) {
+			return source.ErrDuplicateMigration{
+				Migration: *m,
+				FileInfo:  file,
+			}
+		}
+		parsed = append(parsed, struct {
+			m    *source.Migration
+			file os.FileInfo
+		}{m, file})
+	}
+
+	// Re-check each staged entry against migrations already bound from
+	// earlier mounts before touching any shared state.
+	for _, p := range parsed {
+		if n.migrationExists(p.m) {
+			return source.ErrDuplicateMigration{
+				Migration: *p.m,
+				FileInfo:  p.file,
+			}
+		}
+	}
+
+	idx := len(n.mounts)
+	for _, p := range parsed {
+		n.migrations.Append(p.m)
+		n.owner[mountKey{version: p.m.Version, direction: p.m.Direction}] = idx
+	}
+	n.mounts = append(n.mounts, mount{prefix: prefix, fsys: fsys, path: subpath})
+	return nil
+}
+
+// migrationExists reports whether a migration with m's version and
+// direction has already been bound into n.migrations.
+func (n *NameSpace) migrationExists(m *source.Migration) bool {
+	if m.Direction == source.Down {
+		_, ok := n.migrations.Down(m.Version)
+		return ok
+	}
+	_, ok := n.migrations.Up(m.Version)
+	return ok
+}
+
+// Close is part of source.Driver interface implementation.
+// Closes every bound filesystem that implements io.Closer, joining any
+// errors encountered.
+func (n *NameSpace) Close() error {
+	var errs []string
+	for _, mnt := range n.mounts {
+		c, ok := mnt.fsys.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", mnt.prefix, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("iofs: close: %s", strings.Join(errs, "; "))
+}
+
+// First is part of source.Driver interface implementation.
+func (n *NameSpace) First() (version uint, err error) {
+	if version, ok := n.migrations.First(); ok {
+		return version, nil
+	}
+	return 0, &fs.PathError{
+		Op:   "first",
+		Path: "namespace",
+		Err:  fs.ErrNotExist,
+	}
+}
+
+// Prev is part of source.Driver interface implementation.
+func (n *NameSpace) Prev(version uint) (prevVersion uint, err error) {
+	if version, ok := n.migrations.Prev(version); ok {
+		return version, nil
+	}
+	return 0, &fs.PathError{
+		Op:   "prev for version " + strconv.FormatUint(uint64(version), 10),
+		Path: "namespace",
+		Err:  fs.ErrNotExist,
+	}
+}
+
+// Next is part of source.Driver interface implementation.
+func (n *NameSpace) Next(version uint) (nextVersion uint, err error) {
+	if version, ok := n.migrations.Next(version); ok {
+		return version, nil
+	}
+	return 0, &fs.PathError{
+		Op:   "next for version " + strconv.FormatUint(uint64(version), 10),
+		Path: "namespace",
+		Err:  fs.ErrNotExist,
+	}
+}
+
+// ReadUp is part of source.Driver interface implementation.
+func (n *NameSpace) ReadUp(version uint) (r io.ReadCloser, identifier string, err error) {
+	if m, ok := n.migrations.Up(version); ok {
+		body, err := n.open(version, m.Direction, m.Raw)
+		if err != nil {
+			return nil, "", err
+		}
+		return body, m.Identifier, nil
+	}
+	return nil, "", &fs.PathError{
+		Op:   "read up for version " + strconv.FormatUint(uint64(version), 10),
+		Path: "namespace",
+		Err:  fs.ErrNotExist,
+	}
+}
+
+// ReadDown is part of source.Driver interface implementation.
+func (n *NameSpace) ReadDown(version uint) (r io.ReadCloser, identifier string, err error) {
+	if m, ok := n.migrations.Down(version); ok {
+		body, err := n.open(version, m.Direction, m.Raw)
+		if err != nil {
+			return nil, "", err
+		}
+		return body, m.Identifier, nil
+	}
+	return nil, "", &fs.PathError{
+		Op:   "read down for version " + strconv.FormatUint(uint64(version), 10),
+		Path: "namespace",
+		Err:  fs.ErrNotExist,
+	}
+}
+
+// open dispatches to the fs.FS that owns version/direction, joining its
+// mount path with the migration's raw filename.
+func (n *NameSpace) open(version uint, direction source.Direction, raw string) (fs.File, error) {
+	idx, ok := n.owner[mountKey{version: version, direction: direction}]
+	if !ok {
+		return nil, &fs.PathError{
+			Op:   "open",
+			Path: raw,
+			Err:  fs.ErrNotExist,
+		}
+	}
+	mnt := n.mounts[idx]
+	return mnt.fsys.Open(path.Join(mnt.path, raw))
+}
@@ -0,0 +1,155 @@
+// +build go1.16
+
+package iofs
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+func TestNameSpaceBindOrdering(t *testing.T) {
+	core := fstest.MapFS{
+		"migrations/1_core_init.up.sql":   {Data: []byte("SELECT 1")},
+		"migrations/1_core_init.down.sql": {Data: []byte("SELECT 1")},
+		"migrations/3_core_third.up.sql":  {Data: []byte("SELECT 1")},
+	}
+	plugin := fstest.MapFS{
+		"plugin/2_plugin_second.up.sql": {Data: []byte("SELECT 1")},
+		"plugin/4_plugin_fourth.up.sql": {Data: []byte("SELECT 1")},
+	}
+
+	ns := NewNameSpace()
+	if err := ns.Bind("core", core, "migrations"); err != nil {
+		t.Fatalf("bind core: %v", err)
+	}
+	if err := ns.Bind("plugin", plugin, "plugin"); err != nil {
+		t.Fatalf("bind plugin: %v", err)
+	}
+
+	first, err := ns.First()
+	if err != nil {
+		t.Fatalf("First: %v", err)
+	}
+	if first != 1 {
+		t.Fatalf("expected first version 1, got %d", first)
+	}
+
+	var order []uint
+	for v := first; ; {
+		order = append(order, v)
+		next, err := ns.Next(v)
+		if err != nil {
+			break
+		}
+		v = next
+	}
+	want := []uint{1, 2, 3, 4}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+
+	r, identifier, err := ns.ReadUp(2)
+	if err != nil {
+		t.Fatalf("ReadUp(2): %v", err)
+	}
+	defer r.Close()
+	if identifier != "plugin_second" {
+		t.Fatalf("expected identifier plugin_second, got %s", identifier)
+	}
+}
+
+func TestNameSpaceBindSplitDirectionAcrossMounts(t *testing.T) {
+	core := fstest.MapFS{
+		"migrations/5_widgets.up.sql": {Data: []byte("CREATE TABLE widgets (id int)")},
+	}
+	rollback := fstest.MapFS{
+		"rollback/5_widgets.down.sql": {Data: []byte("DROP TABLE widgets")},
+	}
+
+	ns := NewNameSpace()
+	if err := ns.Bind("core", core, "migrations"); err != nil {
+		t.Fatalf("bind core: %v", err)
+	}
+	// Same version, opposite direction, different mount: this is not a
+	// duplicate and must not overwrite core's ownership of the up side.
+	if err := ns.Bind("rollback", rollback, "rollback"); err != nil {
+		t.Fatalf("bind rollback: %v", err)
+	}
+
+	up, _, err := ns.ReadUp(5)
+	if err != nil {
+		t.Fatalf("ReadUp(5): %v", err)
+	}
+	up.Close()
+
+	down, _, err := ns.ReadDown(5)
+	if err != nil {
+		t.Fatalf("ReadDown(5): %v", err)
+	}
+	down.Close()
+}
+
+func TestNameSpaceBindRollsBackOnIntraMountDuplicate(t *testing.T) {
+	core := fstest.MapFS{
+		"migrations/1_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+	broken := fstest.MapFS{
+		// 10_a and 10_b both parse to version 10, up direction: a
+		// duplicate partway through this mount's entries.
+		"broken/09_before.up.sql": {Data: []byte("SELECT 1")},
+		"broken/10_a.up.sql":      {Data: []byte("SELECT 1")},
+		"broken/10_b.up.sql":      {Data: []byte("SELECT 1")},
+	}
+
+	ns := NewNameSpace()
+	if err := ns.Bind("core", core, "migrations"); err != nil {
+		t.Fatalf("bind core: %v", err)
+	}
+
+	if err := ns.Bind("broken", broken, "broken"); err == nil {
+		t.Fatal("expected duplicate migration error, got nil")
+	}
+
+	if got := len(ns.mounts); got != 1 {
+		t.Fatalf("expected the broken mount to be rolled back, got %d mounts", got)
+	}
+	// version 9, parsed before the duplicate was hit, must not have been
+	// left half-registered: it should read back as not found, not panic.
+	if _, _, err := ns.ReadUp(9); err == nil {
+		t.Fatal("expected version 9 from the rolled-back mount to be absent")
+	}
+}
+
+func TestNameSpaceBindDuplicateAcrossMounts(t *testing.T) {
+	core := fstest.MapFS{
+		"migrations/1_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+	tenant := fstest.MapFS{
+		"tenant/1_also_init.up.sql": {Data: []byte("SELECT 1")},
+	}
+
+	ns := NewNameSpace()
+	if err := ns.Bind("core", core, "migrations"); err != nil {
+		t.Fatalf("bind core: %v", err)
+	}
+
+	err := ns.Bind("tenant", tenant, "tenant")
+	if err == nil {
+		t.Fatal("expected duplicate migration error, got nil")
+	}
+	if _, ok := err.(source.ErrDuplicateMigration); !ok {
+		t.Fatalf("expected source.ErrDuplicateMigration, got %T: %v", err, err)
+	}
+	// ErrDuplicateMigration.Error() dereferences FileInfo; it must be set
+	// on the cross-mount-duplicate path too, or this panics.
+	if msg := err.Error(); msg == "" {
+		t.Fatal("expected non-empty error message")
+	}
+}